@@ -0,0 +1,116 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+)
+
+const (
+	// InstanceTypeCatalogRuntime fetches instance types from the cloud provider on every Refresh.
+	InstanceTypeCatalogRuntime = "runtime"
+	// InstanceTypeCatalogStatic serves a fixed, built-in instance type table and never calls the cloud provider.
+	InstanceTypeCatalogStatic = "static"
+	// InstanceTypeCatalogHybrid refreshes from the cloud provider like runtime, but falls back to the
+	// static table for instance types the cloud provider doesn't (yet) report.
+	InstanceTypeCatalogHybrid = "hybrid"
+)
+
+// NewInstanceTypeCatalog builds the InstanceTypeCatalog for the given mode.
+func NewInstanceTypeCatalog(mode string, cloudProvider cloudprovider.CloudProvider) (cloudprovider.InstanceTypeCatalog, error) {
+	switch mode {
+	case InstanceTypeCatalogStatic, "":
+		return &cachingCatalog{static: staticInstanceTypes}, nil
+	case InstanceTypeCatalogRuntime:
+		lister, ok := cloudProvider.(cloudprovider.InstanceTypeLister)
+		if !ok {
+			return nil, fmt.Errorf("cloud provider does not support runtime instance type listing")
+		}
+		return &cachingCatalog{lister: lister}, nil
+	case InstanceTypeCatalogHybrid:
+		lister, ok := cloudProvider.(cloudprovider.InstanceTypeLister)
+		if !ok {
+			return nil, fmt.Errorf("cloud provider does not support runtime instance type listing")
+		}
+		return &cachingCatalog{lister: lister, static: staticInstanceTypes}, nil
+	default:
+		return nil, fmt.Errorf("unknown instance type catalog mode: %s", mode)
+	}
+}
+
+// cachingCatalog caches instance types refreshed from an optional InstanceTypeLister, falling
+// back to a static table for names the lister doesn't report.
+type cachingCatalog struct {
+	lister cloudprovider.InstanceTypeLister
+	static map[string]cloudprovider.InstanceType
+
+	mutex  sync.RWMutex
+	cached map[string]cloudprovider.InstanceType
+}
+
+// GetInstanceType returns the InstanceType for name, preferring the cache refreshed from the
+// cloud provider and falling back to the static table.
+func (c *cachingCatalog) GetInstanceType(name string) (cloudprovider.InstanceType, error) {
+	c.mutex.RLock()
+	instanceType, found := c.cached[name]
+	c.mutex.RUnlock()
+	if found {
+		return instanceType, nil
+	}
+	if instanceType, found := c.static[name]; found {
+		return instanceType, nil
+	}
+	return cloudprovider.InstanceType{}, fmt.Errorf("unknown instance type: %s", name)
+}
+
+// Refresh re-populates the cache from the cloud provider. It's a no-op for the static-only catalog.
+func (c *cachingCatalog) Refresh() error {
+	if c.lister == nil {
+		return nil
+	}
+	instanceTypes, err := c.lister.InstanceTypes()
+	if err != nil {
+		return err
+	}
+	cached := make(map[string]cloudprovider.InstanceType, len(instanceTypes))
+	for _, it := range instanceTypes {
+		cached[it.Name] = it
+	}
+	c.mutex.Lock()
+	c.cached = cached
+	c.mutex.Unlock()
+	return nil
+}
+
+// RefreshLoop calls Refresh every interval until stopCh is closed. It's meant to be run in its
+// own goroutine by the caller that owns the catalog's lifecycle.
+func RefreshLoop(catalog cloudprovider.InstanceTypeCatalog, interval time.Duration, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			catalog.Refresh()
+		case <-stopCh:
+			return
+		}
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+)
+
+type fakeInstanceTypeLister struct {
+	instanceTypes []cloudprovider.InstanceType
+	err           error
+}
+
+func (l *fakeInstanceTypeLister) InstanceTypes() ([]cloudprovider.InstanceType, error) {
+	return l.instanceTypes, l.err
+}
+
+func TestCachingCatalogGetInstanceTypeFallsBackToStatic(t *testing.T) {
+	c := &cachingCatalog{
+		static: map[string]cloudprovider.InstanceType{
+			"m5.large": {Name: "m5.large", CPU: 2, MemoryMb: 8192},
+		},
+	}
+
+	instanceType, err := c.GetInstanceType("m5.large")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if instanceType.CPU != 2 {
+		t.Errorf("expected the static table entry, got %+v", instanceType)
+	}
+}
+
+func TestCachingCatalogGetInstanceTypeUnknown(t *testing.T) {
+	c := &cachingCatalog{}
+
+	if _, err := c.GetInstanceType("does-not-exist"); err == nil {
+		t.Error("expected an error for an unknown instance type")
+	}
+}
+
+func TestCachingCatalogRefreshPrefersCacheOverStatic(t *testing.T) {
+	c := &cachingCatalog{
+		lister: &fakeInstanceTypeLister{instanceTypes: []cloudprovider.InstanceType{
+			{Name: "m5.large", CPU: 2, PricePerHour: 0.096},
+		}},
+		static: map[string]cloudprovider.InstanceType{
+			"m5.large": {Name: "m5.large", CPU: 2, PricePerHour: 999},
+		},
+	}
+
+	if err := c.Refresh(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	instanceType, err := c.GetInstanceType("m5.large")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if instanceType.PricePerHour != 0.096 {
+		t.Errorf("expected the refreshed price to take precedence over the static table, got %+v", instanceType)
+	}
+}
+
+func TestCachingCatalogRefreshPropagatesError(t *testing.T) {
+	c := &cachingCatalog{lister: &fakeInstanceTypeLister{err: fmt.Errorf("boom")}}
+
+	if err := c.Refresh(); err == nil {
+		t.Error("expected Refresh to propagate the lister's error")
+	}
+}
+
+func TestCachingCatalogRefreshNoopWithoutLister(t *testing.T) {
+	c := &cachingCatalog{}
+
+	if err := c.Refresh(); err != nil {
+		t.Errorf("expected Refresh to be a no-op without a lister, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import "k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+
+// staticInstanceTypes is a built-in table of common general-purpose instance types, used by the
+// "static" and "hybrid" catalog modes. It's not exhaustive; it exists so that price/CPU/memory-aware
+// expanders have something to work with out of the box, without a round trip to the cloud provider.
+// Prices are approximate on-demand US list prices and are only meant as relative ordering hints.
+var staticInstanceTypes = map[string]cloudprovider.InstanceType{
+	// AWS
+	"t3.medium":  {Name: "t3.medium", CPU: 2, MemoryMb: 4096, PricePerHour: 0.0416},
+	"m5.large":   {Name: "m5.large", CPU: 2, MemoryMb: 8192, PricePerHour: 0.096},
+	"m5.xlarge":  {Name: "m5.xlarge", CPU: 4, MemoryMb: 16384, PricePerHour: 0.192},
+	"m5.2xlarge": {Name: "m5.2xlarge", CPU: 8, MemoryMb: 32768, PricePerHour: 0.384},
+	"c5.large":   {Name: "c5.large", CPU: 2, MemoryMb: 4096, PricePerHour: 0.085},
+	"c5.xlarge":  {Name: "c5.xlarge", CPU: 4, MemoryMb: 8192, PricePerHour: 0.17},
+	"r5.large":   {Name: "r5.large", CPU: 2, MemoryMb: 16384, PricePerHour: 0.126},
+	"r5.xlarge":  {Name: "r5.xlarge", CPU: 4, MemoryMb: 32768, PricePerHour: 0.252},
+
+	// GCE
+	"n1-standard-1": {Name: "n1-standard-1", CPU: 1, MemoryMb: 3840, PricePerHour: 0.0475},
+	"n1-standard-2": {Name: "n1-standard-2", CPU: 2, MemoryMb: 7680, PricePerHour: 0.095},
+	"n1-standard-4": {Name: "n1-standard-4", CPU: 4, MemoryMb: 15360, PricePerHour: 0.19},
+	"n1-standard-8": {Name: "n1-standard-8", CPU: 8, MemoryMb: 30720, PricePerHour: 0.38},
+	"n1-highmem-2":  {Name: "n1-highmem-2", CPU: 2, MemoryMb: 13312, PricePerHour: 0.1184},
+	"n1-highmem-4":  {Name: "n1-highmem-4", CPU: 4, MemoryMb: 26624, PricePerHour: 0.2368},
+	"n1-highcpu-4":  {Name: "n1-highcpu-4", CPU: 4, MemoryMb: 3600, PricePerHour: 0.1416},
+}
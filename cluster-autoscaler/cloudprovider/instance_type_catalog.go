@@ -0,0 +1,46 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+// InstanceType describes the capacity and pricing of a cloud provider instance type.
+type InstanceType struct {
+	// Name is the cloud provider's identifier for this instance type, e.g. "m5.large".
+	Name string
+	// CPU is the number of allocatable CPU cores.
+	CPU int64
+	// MemoryMb is the amount of allocatable memory, in megabytes.
+	MemoryMb int64
+	// PricePerHour is the on-demand price of the instance type, in the cloud provider's billing currency.
+	PricePerHour float64
+}
+
+// InstanceTypeCatalog resolves instance type names to their capacity and pricing, so expanders
+// can make price/CPU/memory-aware decisions without hitting the cloud provider API on every loop.
+type InstanceTypeCatalog interface {
+	// GetInstanceType returns the InstanceType for the given name, as known at the last Refresh.
+	GetInstanceType(name string) (InstanceType, error)
+	// Refresh updates the catalog's cached instance types.
+	Refresh() error
+}
+
+// InstanceTypeLister is implemented by CloudProviders that can enumerate the instance types
+// available to them at runtime. InstanceTypeCatalogs in "runtime" or "hybrid" mode use it to
+// refresh their cache.
+type InstanceTypeLister interface {
+	// InstanceTypes returns the instance types currently known to the cloud provider.
+	InstanceTypes() ([]InstanceType, error)
+}
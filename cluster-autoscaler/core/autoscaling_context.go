@@ -19,6 +19,7 @@ package core
 import (
 	"time"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/builder"
 	"k8s.io/autoscaler/cluster-autoscaler/clusterstate"
@@ -26,6 +27,8 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/expander"
 	"k8s.io/autoscaler/cluster-autoscaler/expander/factory"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+	"k8s.io/autoscaler/cluster-autoscaler/spotrescheduler"
+	spotreschedulerfactory "k8s.io/autoscaler/cluster-autoscaler/spotrescheduler/factory"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
 	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
 	kube_client "k8s.io/client-go/kubernetes"
@@ -49,8 +52,29 @@ type AutoscalingContext struct {
 	PredicateChecker *simulator.PredicateChecker
 	// ExpanderStrategy is the strategy used to choose which node group to expand when scaling up
 	ExpanderStrategy expander.Strategy
+	// ListerRegistry provides listers CA uses to scan the cluster, scoped according to PodSelector.
+	ListerRegistry kube_util.ListerRegistry
 	// LogRecorder can be used to collect log messages to expose via Events on some central object.
 	LogRecorder *utils.LogEventRecorder
+	// NodeAutoRepair finds and replaces broken nodes, when enabled.
+	NodeAutoRepair *NodeAutoRepair
+	// SpotReschedulerStrategy is the strategy used to migrate pods from on-demand to spot node groups
+	SpotReschedulerStrategy spotrescheduler.Strategy
+	// InstanceTypeCatalog resolves instance type names to capacity and pricing for price/CPU/memory-aware expanders
+	InstanceTypeCatalog cloudprovider.InstanceTypeCatalog
+}
+
+// PodSelector narrows down which pods are considered by CA, so a single deployment
+// can evaluate most of the cluster while ignoring e.g. system or batch-only namespaces.
+type PodSelector struct {
+	// IncludeNamespaces restricts scanning to these namespaces. An empty slice means all namespaces.
+	IncludeNamespaces []string
+	// ExcludeNamespaces excludes these namespaces from scanning, applied after IncludeNamespaces.
+	ExcludeNamespaces []string
+	// IncludeLabelSelector restricts scanning to pods matching this label selector.
+	IncludeLabelSelector labels.Selector
+	// ExcludeLabelSelector excludes pods matching this label selector, applied after IncludeLabelSelector.
+	ExcludeLabelSelector labels.Selector
 }
 
 // AutoscalingOptions contain various options to customize how autoscaling works
@@ -93,31 +117,88 @@ type AutoscalingOptions struct {
 	// ScaleDownEnabled is used to allow CA to scale down the cluster
 	ScaleDownEnabled bool
 	// ScaleDownDelay sets the duration from the last scale up to the time when CA starts to check scale down options
+	//
+	// Deprecated: use ScaleDownDelayAfterAdd instead.
 	ScaleDownDelay time.Duration
 	// ScaleDownTrialInterval sets how often scale down possibility is check
+	//
+	// Deprecated: use ScanInterval instead.
 	ScaleDownTrialInterval time.Duration
+	// ScaleDownDelayAfterAdd sets the duration from the last scale up to the time when CA starts to check scale down options
+	ScaleDownDelayAfterAdd time.Duration
+	// ScaleDownDelayAfterDelete sets the duration between scale down and the next scale down check, when unset it defaults to ScanInterval
+	ScaleDownDelayAfterDelete time.Duration
+	// ScaleDownDelayAfterFailure sets the duration to wait after a failed scale down before trying again
+	ScaleDownDelayAfterFailure time.Duration
+	// ScanInterval sets how often the autoscaler checks for scale up/down options
+	ScanInterval time.Duration
+	// NewPodScaleUpDelay sets the duration during which pods are ignored for scale up, counted from the pod's
+	// creation time, unless a pod overrides it via the PodScaleUpDelayAnnotationKey annotation
+	NewPodScaleUpDelay time.Duration
+	// SkipNodesWithLocalStorage tells if the autoscaler should skip nodes with pods using local storage when scaling down
+	SkipNodesWithLocalStorage bool
+	// SkipNodesWithSystemPods tells if the autoscaler should skip nodes with system pods (e.g. kube-system) when scaling down
+	SkipNodesWithSystemPods bool
 	// WriteStatusConfigMap tells if the status information should be written to a ConfigMap
 	WriteStatusConfigMap bool
 	// BalanceSimilarNodeGroups enables logic that identifies node groups with similar machines and tries to balance node count between them.
 	BalanceSimilarNodeGroups bool
 	// ConfigNamespace is the namespace cluster-autoscaler is running in and all related configmaps live in
 	ConfigNamespace string
-	// NamespaceFilter limits scanning for pods to be within this namespace
-	NamespaceFilter string
+	// PodSelector limits scanning for pods to those matching the included/excluded namespaces and label selectors
+	PodSelector PodSelector
+	// NodeAutoRepairEnabled tells CA to look for broken nodes and replace them
+	NodeAutoRepairEnabled bool
+	// NodeAutoRepairUnhealthyDuration is how long a node has to be unhealthy (NotReady,
+	// unregistered past UnregisteredNodeRemovalTime, or failing node-problem-detector
+	// conditions) before it is repaired
+	NodeAutoRepairUnhealthyDuration time.Duration
+	// MaxConcurrentRepairs is the maximum number of nodes that can be repaired at the same time
+	MaxConcurrentRepairs int
+	// SpotReschedulerEnabled enables migrating pods from on-demand nodes to spot node groups where they fit
+	SpotReschedulerEnabled bool
+	// SpotNodeGroupLabel is the node label that marks a node as belonging to a spot/preemptible node group
+	SpotNodeGroupLabel string
+	// OnDemandNodeGroupLabel is the node label that marks a node as belonging to an on-demand node group
+	OnDemandNodeGroupLabel string
+	// MaxPodEvictionTime is the maximum time the spot rescheduler waits for a pod to be evicted
+	MaxPodEvictionTime time.Duration
+	// InstanceTypeCatalogMode sets how instance type capacity/pricing is sourced: "runtime", "static", or "hybrid"
+	InstanceTypeCatalogMode string
+	// InstanceTypeCatalogRefreshInterval sets how often the instance type catalog is refreshed from the cloud provider
+	InstanceTypeCatalogRefreshInterval time.Duration
 }
 
-// NewAutoscalingContext returns an autoscaling context from all the necessary parameters passed via arguments
+// NewAutoscalingContext returns an autoscaling context from all the necessary parameters passed via arguments.
+// stopChannel controls the lifetime of background loops started here, such as the instance type
+// catalog refresh; it should be closed when the caller is shutting down.
 func NewAutoscalingContext(options AutoscalingOptions, predicateChecker *simulator.PredicateChecker,
 	kubeClient kube_client.Interface, kubeEventRecorder kube_record.EventRecorder,
-	logEventRecorder *utils.LogEventRecorder, listerRegistry kube_util.ListerRegistry) (*AutoscalingContext, errors.AutoscalerError) {
+	logEventRecorder *utils.LogEventRecorder,
+	stopChannel <-chan struct{}) (*AutoscalingContext, errors.AutoscalerError) {
+
+	listerRegistry := kube_util.NewListerRegistryWithSelectors(kubeClient, options.PodSelector.IncludeNamespaces,
+		options.PodSelector.ExcludeNamespaces, options.PodSelector.IncludeLabelSelector, options.PodSelector.ExcludeLabelSelector)
 
 	cloudProviderBuilder := builder.NewCloudProviderBuilder(options.CloudProviderName, options.CloudConfig)
 	cloudProvider := cloudProviderBuilder.Build(cloudprovider.NodeGroupDiscoveryOptions{
 		NodeGroupSpecs:             options.NodeGroups,
 		NodeGroupAutoDiscoverySpec: options.NodeGroupAutoDiscovery,
 	})
+
+	instanceTypeCatalog, catalogErr := builder.NewInstanceTypeCatalog(options.InstanceTypeCatalogMode, cloudProvider)
+	if catalogErr != nil {
+		return nil, errors.NewAutoscalerError(errors.InternalError, catalogErr.Error())
+	}
+	if err := instanceTypeCatalog.Refresh(); err != nil {
+		return nil, errors.NewAutoscalerError(errors.CloudProviderError, err.Error())
+	}
+	if options.InstanceTypeCatalogRefreshInterval > 0 {
+		go builder.RefreshLoop(instanceTypeCatalog, options.InstanceTypeCatalogRefreshInterval, stopChannel)
+	}
+
 	expanderStrategy, err := factory.ExpanderStrategyFromString(options.ExpanderName,
-		cloudProvider, listerRegistry.AllNodeLister())
+		cloudProvider, listerRegistry.AllNodeLister(), instanceTypeCatalog)
 	if err != nil {
 		return nil, err
 	}
@@ -128,16 +209,28 @@ func NewAutoscalingContext(options AutoscalingOptions, predicateChecker *simulat
 	}
 	clusterStateRegistry := clusterstate.NewClusterStateRegistry(cloudProvider, clusterStateConfig)
 
+	if options.ScaleDownDelayAfterDelete == 0 {
+		options.ScaleDownDelayAfterDelete = options.ScanInterval
+	}
+
+	spotReschedulerStrategy := spotreschedulerfactory.NewSpotReschedulerStrategy(options.SpotReschedulerEnabled,
+		kubeClient, predicateChecker, kubeEventRecorder, options.OnDemandNodeGroupLabel, options.SpotNodeGroupLabel,
+		options.MaxPodEvictionTime, options.MaxGracefulTerminationSec)
+
 	autoscalingContext := AutoscalingContext{
-		AutoscalingOptions:   options,
-		CloudProvider:        cloudProvider,
-		ClusterStateRegistry: clusterStateRegistry,
-		ClientSet:            kubeClient,
-		Recorder:             kubeEventRecorder,
-		PredicateChecker:     predicateChecker,
-		ExpanderStrategy:     expanderStrategy,
-		LogRecorder:          logEventRecorder,
+		AutoscalingOptions:      options,
+		CloudProvider:           cloudProvider,
+		ClusterStateRegistry:    clusterStateRegistry,
+		ClientSet:               kubeClient,
+		Recorder:                kubeEventRecorder,
+		PredicateChecker:        predicateChecker,
+		ExpanderStrategy:        expanderStrategy,
+		ListerRegistry:          listerRegistry,
+		LogRecorder:             logEventRecorder,
+		SpotReschedulerStrategy: spotReschedulerStrategy,
+		InstanceTypeCatalog:     instanceTypeCatalog,
 	}
+	autoscalingContext.NodeAutoRepair = NewNodeAutoRepair(&autoscalingContext)
 
 	return &autoscalingContext, nil
 }
@@ -0,0 +1,257 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/autoscaler/cluster-autoscaler/utils/nodes"
+
+	"github.com/golang/glog"
+)
+
+// NodeAutoRepair periodically looks for broken nodes and replaces them by
+// draining and asking the CloudProvider to recreate their backing instance.
+type NodeAutoRepair struct {
+	context *AutoscalingContext
+}
+
+// NewNodeAutoRepair creates a NodeAutoRepair tied to the given context.
+func NewNodeAutoRepair(context *AutoscalingContext) *NodeAutoRepair {
+	return &NodeAutoRepair{
+		context: context,
+	}
+}
+
+// RepairBrokenNodes finds nodes that have been broken for longer than
+// NodeAutoRepairUnhealthyDuration and replaces them, up to MaxConcurrentRepairs
+// at a time. It refuses to act while the cluster is unhealthy, so that a
+// partial outage doesn't trigger a mass replacement.
+func (nar *NodeAutoRepair) RepairBrokenNodes(allNodes []*apiv1.Node, currentTime time.Time) error {
+	if !nar.context.NodeAutoRepairEnabled {
+		return nil
+	}
+	if !nar.context.ClusterStateRegistry.IsClusterHealthy() {
+		glog.V(2).Info("Skipping node auto-repair: cluster is not healthy")
+		return nil
+	}
+
+	broken := nar.findBrokenNodes(allNodes, currentTime)
+	for _, unregistered := range nar.findUnregisteredBrokenNodes(currentTime) {
+		if !nodes.ContainsNode(nodeNames(broken), unregistered.Name) {
+			broken = append(broken, unregistered)
+		}
+	}
+	if len(broken) == 0 {
+		return nil
+	}
+	if len(broken) > nar.context.MaxConcurrentRepairs {
+		broken = broken[:nar.context.MaxConcurrentRepairs]
+	}
+
+	healthy := nar.healthyNodes(allNodes, broken)
+	for _, node := range broken {
+		if err := nar.repairNode(node, healthy); err != nil {
+			glog.Errorf("Failed to auto-repair node %s: %v", node.Name, err)
+		}
+	}
+	return nil
+}
+
+func (nar *NodeAutoRepair) findBrokenNodes(allNodes []*apiv1.Node, currentTime time.Time) []*apiv1.Node {
+	var broken []*apiv1.Node
+	for _, node := range allNodes {
+		if nar.isBroken(node, currentTime) {
+			broken = append(broken, node)
+		}
+	}
+	return broken
+}
+
+// findUnregisteredBrokenNodes returns nodes the cloud provider still has instances for, but that
+// never joined the cluster within UnregisteredNodeRemovalTime.
+func (nar *NodeAutoRepair) findUnregisteredBrokenNodes(currentTime time.Time) []*apiv1.Node {
+	var broken []*apiv1.Node
+	for _, unregistered := range nar.context.ClusterStateRegistry.GetUnregisteredNodes() {
+		if currentTime.Sub(unregistered.UnregisteredSince) > nar.context.UnregisteredNodeRemovalTime {
+			broken = append(broken, unregistered.Node)
+		}
+	}
+	return broken
+}
+
+// isBroken reports whether a node has been NotReady or reporting a failing node-problem-detector
+// condition for longer than NodeAutoRepairUnhealthyDuration. All conditions are inspected, since a
+// node can be both NotReady and failing a node-problem-detector check at once.
+func (nar *NodeAutoRepair) isBroken(node *apiv1.Node, currentTime time.Time) bool {
+	for _, condition := range node.Status.Conditions {
+		isFailing := (condition.Type == apiv1.NodeReady && condition.Status != apiv1.ConditionTrue) ||
+			isNodeProblemDetectorFailure(condition)
+		if isFailing && currentTime.Sub(condition.LastTransitionTime.Time) > nar.context.NodeAutoRepairUnhealthyDuration {
+			return true
+		}
+	}
+	return false
+}
+
+func isNodeProblemDetectorFailure(condition apiv1.NodeCondition) bool {
+	switch condition.Type {
+	case "KernelDeadlock", "ReadonlyFilesystem", "FrequentUnregisterNetDevice":
+		return condition.Status == apiv1.ConditionTrue
+	default:
+		return false
+	}
+}
+
+// healthyNodes returns allNodes minus broken, for use as drain targets when checking that evicted
+// pods have somewhere to go.
+func (nar *NodeAutoRepair) healthyNodes(allNodes []*apiv1.Node, broken []*apiv1.Node) []*apiv1.Node {
+	brokenNames := nodeNames(broken)
+	var healthy []*apiv1.Node
+	for _, node := range allNodes {
+		if !nodes.ContainsNode(brokenNames, node.Name) {
+			healthy = append(healthy, node)
+		}
+	}
+	return healthy
+}
+
+func nodeNames(list []*apiv1.Node) []string {
+	names := make([]string, 0, len(list))
+	for _, node := range list {
+		names = append(names, node.Name)
+	}
+	return names
+}
+
+// repairNode drains the node and asks its NodeGroup to delete and recreate
+// the backing instance, then records a NodeRepair event.
+func (nar *NodeAutoRepair) repairNode(node *apiv1.Node, healthyNodes []*apiv1.Node) error {
+	nodeGroup, err := nar.context.CloudProvider.NodeGroupForNode(node)
+	if err != nil {
+		return err
+	}
+	if nodeGroup == nil {
+		glog.V(4).Infof("Node %s has no node group, skipping auto-repair", node.Name)
+		return nil
+	}
+
+	glog.V(0).Infof("Auto-repairing node %s in node group %s", node.Name, nodeGroup.Id())
+	nar.context.Recorder.Eventf(node, apiv1.EventTypeWarning, "NodeRepair",
+		"Node %s is broken, draining and recreating it", node.Name)
+
+	if err := nar.drainNode(node, healthyNodes); err != nil {
+		return err
+	}
+
+	if err := nodeGroup.DeleteNodes([]*apiv1.Node{node}); err != nil {
+		return err
+	}
+	if err := nodeGroup.IncreaseSize(1); err != nil {
+		return err
+	}
+
+	if nar.context.WriteStatusConfigMap {
+		nar.context.LogRecorder.Eventf(apiv1.EventTypeWarning, "NodeRepair",
+			"Repaired broken node %s in node group %s", node.Name, nodeGroup.Id())
+	}
+	return nil
+}
+
+// drainNode cordons the node, then evicts its pods through the eviction subresource so that
+// PodDisruptionBudgets are honored, giving each MaxGracefulTerminationSec to terminate and
+// retrying evictions blocked by a budget until the same deadline elapses. It waits for each pod to
+// actually disappear from the API server before moving on to the next one. PredicateChecker is
+// used to warn when a pod has nowhere else to be scheduled, since the node is going away regardless.
+func (nar *NodeAutoRepair) drainNode(node *apiv1.Node, healthyNodes []*apiv1.Node) error {
+	node.Spec.Unschedulable = true
+	if _, err := nar.context.ClientSet.CoreV1().Nodes().Update(node); err != nil {
+		return err
+	}
+
+	fieldSelector := "spec.nodeName=" + node.Name
+	podList, err := nar.context.ClientSet.CoreV1().Pods(apiv1.NamespaceAll).List(metav1.ListOptions{FieldSelector: fieldSelector})
+	if err != nil {
+		return err
+	}
+
+	gracePeriod := int64(nar.context.MaxGracefulTerminationSec)
+	gracePeriodDuration := time.Duration(nar.context.MaxGracefulTerminationSec) * time.Second
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !nar.hasSchedulingTarget(pod, healthyNodes) {
+			glog.V(2).Infof("No healthy node currently fits pod %s/%s evicted from %s", pod.Namespace, pod.Name, node.Name)
+		}
+
+		deadline := time.Now().Add(gracePeriodDuration)
+		if err := nar.evictPod(pod, gracePeriod, deadline); err != nil {
+			return err
+		}
+		if err := nar.waitForPodTermination(pod, deadline); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (nar *NodeAutoRepair) evictPod(pod *apiv1.Pod, gracePeriod int64, deadline time.Time) error {
+	eviction := &policyv1beta1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+		DeleteOptions: &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod},
+	}
+	for {
+		err := nar.context.ClientSet.PolicyV1beta1().Evictions(pod.Namespace).Evict(eviction)
+		if err == nil || apierrors.IsNotFound(err) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+func (nar *NodeAutoRepair) waitForPodTermination(pod *apiv1.Pod, deadline time.Time) error {
+	for {
+		_, err := nar.context.ClientSet.CoreV1().Pods(pod.Namespace).Get(pod.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("pod %s/%s did not terminate within %ds", pod.Namespace, pod.Name, nar.context.MaxGracefulTerminationSec)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+func (nar *NodeAutoRepair) hasSchedulingTarget(pod *apiv1.Pod, healthyNodes []*apiv1.Node) bool {
+	for _, candidate := range healthyNodes {
+		if err := nar.context.PredicateChecker.CheckPredicates(pod, nil, candidate); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testNodeAutoRepair(unhealthyDuration time.Duration) *NodeAutoRepair {
+	return &NodeAutoRepair{
+		context: &AutoscalingContext{
+			AutoscalingOptions: AutoscalingOptions{
+				NodeAutoRepairUnhealthyDuration: unhealthyDuration,
+			},
+		},
+	}
+}
+
+func nodeWithCondition(name string, conditionType apiv1.NodeConditionType, status apiv1.ConditionStatus, since time.Time) *apiv1.Node {
+	return &apiv1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: apiv1.NodeStatus{
+			Conditions: []apiv1.NodeCondition{
+				{Type: conditionType, Status: status, LastTransitionTime: metav1.NewTime(since)},
+			},
+		},
+	}
+}
+
+func TestIsBrokenNotReadyPastThreshold(t *testing.T) {
+	nar := testNodeAutoRepair(5 * time.Minute)
+	now := time.Now()
+	node := nodeWithCondition("n1", apiv1.NodeReady, apiv1.ConditionFalse, now.Add(-10*time.Minute))
+
+	if !nar.isBroken(node, now) {
+		t.Error("expected a NotReady node past the unhealthy duration to be broken")
+	}
+}
+
+func TestIsBrokenNotReadyButTooRecent(t *testing.T) {
+	nar := testNodeAutoRepair(5 * time.Minute)
+	now := time.Now()
+	node := nodeWithCondition("n1", apiv1.NodeReady, apiv1.ConditionFalse, now.Add(-time.Minute))
+
+	if nar.isBroken(node, now) {
+		t.Error("did not expect a recently-NotReady node to be broken yet")
+	}
+}
+
+func TestIsBrokenNodeProblemDetectorCondition(t *testing.T) {
+	nar := testNodeAutoRepair(5 * time.Minute)
+	now := time.Now()
+	node := nodeWithCondition("n1", "KernelDeadlock", apiv1.ConditionTrue, now.Add(-10*time.Minute))
+
+	if !nar.isBroken(node, now) {
+		t.Error("expected a node with a failing node-problem-detector condition to be broken")
+	}
+}
+
+func TestIsBrokenHealthyNode(t *testing.T) {
+	nar := testNodeAutoRepair(5 * time.Minute)
+	now := time.Now()
+	node := nodeWithCondition("n1", apiv1.NodeReady, apiv1.ConditionTrue, now.Add(-time.Hour))
+
+	if nar.isBroken(node, now) {
+		t.Error("did not expect a Ready node to be broken")
+	}
+}
+
+func TestFindBrokenNodes(t *testing.T) {
+	nar := testNodeAutoRepair(5 * time.Minute)
+	now := time.Now()
+	healthy := nodeWithCondition("healthy", apiv1.NodeReady, apiv1.ConditionTrue, now.Add(-time.Hour))
+	broken := nodeWithCondition("broken", apiv1.NodeReady, apiv1.ConditionFalse, now.Add(-10*time.Minute))
+
+	got := nar.findBrokenNodes([]*apiv1.Node{healthy, broken}, now)
+
+	if len(got) != 1 || got[0].Name != "broken" {
+		t.Errorf("expected only the broken node to be returned, got %v", got)
+	}
+}
+
+func TestNodeNames(t *testing.T) {
+	nodeList := []*apiv1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+	}
+
+	got := nodeNames(nodeList)
+
+	if len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("unexpected node names: %v", got)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+
+	"github.com/golang/glog"
+)
+
+// PodScaleUpDelayAnnotationKey lets an individual pod override NewPodScaleUpDelay, e.g. to shorten
+// the delay for latency-sensitive pods or lengthen it for ones that are known to take a while to
+// become ready. The value must parse as a time.Duration (e.g. "30s").
+const PodScaleUpDelayAnnotationKey = "cluster-autoscaler.kubernetes.io/new-pod-scale-up-delay"
+
+// PodScaleUpDelay returns the duration pod should be ignored for scale-up purposes, counted from
+// its creation time: the pod's own PodScaleUpDelayAnnotationKey annotation if present and valid,
+// otherwise NewPodScaleUpDelay.
+func (context *AutoscalingContext) PodScaleUpDelay(pod *apiv1.Pod) time.Duration {
+	if value, found := pod.Annotations[PodScaleUpDelayAnnotationKey]; found {
+		if delay, err := time.ParseDuration(value); err == nil {
+			return delay
+		}
+		glog.Warningf("Pod %s/%s has an invalid %s annotation value %q, falling back to NewPodScaleUpDelay",
+			pod.Namespace, pod.Name, PodScaleUpDelayAnnotationKey, value)
+	}
+	return context.NewPodScaleUpDelay
+}
+
+// IsPodNewlyCreated reports whether pod is still within its scale-up delay as of now, and so
+// should be ignored as a scale-up trigger.
+func (context *AutoscalingContext) IsPodNewlyCreated(pod *apiv1.Pod, now time.Time) bool {
+	return now.Sub(pod.CreationTimestamp.Time) < context.PodScaleUpDelay(pod)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodScaleUpDelayDefault(t *testing.T) {
+	context := &AutoscalingContext{AutoscalingOptions: AutoscalingOptions{NewPodScaleUpDelay: 10 * time.Minute}}
+	pod := &apiv1.Pod{}
+
+	if got := context.PodScaleUpDelay(pod); got != 10*time.Minute {
+		t.Errorf("expected the default NewPodScaleUpDelay, got %v", got)
+	}
+}
+
+func TestPodScaleUpDelayAnnotationOverride(t *testing.T) {
+	context := &AutoscalingContext{AutoscalingOptions: AutoscalingOptions{NewPodScaleUpDelay: 10 * time.Minute}}
+	pod := &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{PodScaleUpDelayAnnotationKey: "30s"},
+		},
+	}
+
+	if got := context.PodScaleUpDelay(pod); got != 30*time.Second {
+		t.Errorf("expected the annotation override, got %v", got)
+	}
+}
+
+func TestPodScaleUpDelayInvalidAnnotationFallsBack(t *testing.T) {
+	context := &AutoscalingContext{AutoscalingOptions: AutoscalingOptions{NewPodScaleUpDelay: 10 * time.Minute}}
+	pod := &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{PodScaleUpDelayAnnotationKey: "not-a-duration"},
+		},
+	}
+
+	if got := context.PodScaleUpDelay(pod); got != 10*time.Minute {
+		t.Errorf("expected fallback to NewPodScaleUpDelay on an invalid annotation, got %v", got)
+	}
+}
+
+func TestIsPodNewlyCreated(t *testing.T) {
+	context := &AutoscalingContext{AutoscalingOptions: AutoscalingOptions{NewPodScaleUpDelay: 10 * time.Minute}}
+	now := time.Now()
+	newPod := &apiv1.Pod{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))}}
+	oldPod := &apiv1.Pod{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))}}
+
+	if !context.IsPodNewlyCreated(newPod, now) {
+		t.Error("expected a pod created within the delay window to be newly created")
+	}
+	if context.IsPodNewlyCreated(oldPod, now) {
+		t.Error("did not expect a pod created well outside the delay window to be newly created")
+	}
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factory
+
+import (
+	"time"
+
+	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+	"k8s.io/autoscaler/cluster-autoscaler/spotrescheduler"
+	kube_client "k8s.io/client-go/kubernetes"
+	kube_record "k8s.io/client-go/tools/record"
+)
+
+// NewSpotReschedulerStrategy builds the SpotReschedulerStrategy to use. It
+// returns a NoOpStrategy when enabled is false.
+func NewSpotReschedulerStrategy(enabled bool, client kube_client.Interface, predicateChecker *simulator.PredicateChecker,
+	recorder kube_record.EventRecorder, onDemandLabel, spotLabel string, maxPodEvictionTime time.Duration,
+	maxGracefulTerminationSec int) spotrescheduler.Strategy {
+	if !enabled {
+		return &spotrescheduler.NoOpStrategy{}
+	}
+	return spotrescheduler.NewLabelStrategy(client, predicateChecker, recorder, onDemandLabel, spotLabel,
+		maxPodEvictionTime, maxGracefulTerminationSec)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spotrescheduler
+
+import (
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+	kube_client "k8s.io/client-go/kubernetes"
+	kube_record "k8s.io/client-go/tools/record"
+
+	"github.com/golang/glog"
+)
+
+// labelStrategy picks on-demand nodes whose pods would fit on a node
+// currently labeled as spot capacity, and drains them so the normal
+// scale-down path can reclaim the on-demand node afterwards.
+type labelStrategy struct {
+	client                    kube_client.Interface
+	predicateChecker          *simulator.PredicateChecker
+	recorder                  kube_record.EventRecorder
+	spotLabel                 string
+	onDemandLabel             string
+	maxPodEvictionTime        time.Duration
+	maxGracefulTerminationSec int
+}
+
+// NewLabelStrategy returns a Strategy that matches on-demand and spot node
+// groups via the given node labels. maxGracefulTerminationSec is used as the
+// grace period for each pod eviction, mirroring the scale-down drain routine.
+func NewLabelStrategy(client kube_client.Interface, predicateChecker *simulator.PredicateChecker,
+	recorder kube_record.EventRecorder, onDemandLabel, spotLabel string, maxPodEvictionTime time.Duration,
+	maxGracefulTerminationSec int) Strategy {
+	return &labelStrategy{
+		client:                    client,
+		predicateChecker:          predicateChecker,
+		recorder:                  recorder,
+		spotLabel:                 spotLabel,
+		onDemandLabel:             onDemandLabel,
+		maxPodEvictionTime:        maxPodEvictionTime,
+		maxGracefulTerminationSec: maxGracefulTerminationSec,
+	}
+}
+
+// Reschedule evicts pods off on-demand nodes that would fit on already
+// running spot nodes, one on-demand node at a time.
+func (s *labelStrategy) Reschedule(nodes []*apiv1.Node) error {
+	onDemand, spot := s.partitionNodes(nodes)
+	if len(spot) == 0 {
+		return nil
+	}
+
+	for _, node := range onDemand {
+		pods, err := s.podsOnNode(node)
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 {
+			continue
+		}
+		target := s.findMigrationTarget(pods, spot)
+		if target == nil {
+			continue
+		}
+		glog.V(1).Infof("Spot rescheduler: migrating pods from on-demand node %s to spot node %s", node.Name, target.Name)
+		if err := s.drainNode(node, pods, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *labelStrategy) partitionNodes(nodes []*apiv1.Node) (onDemand, spot []*apiv1.Node) {
+	for _, node := range nodes {
+		switch {
+		case node.Labels[s.spotLabel] != "":
+			spot = append(spot, node)
+		case node.Labels[s.onDemandLabel] != "":
+			onDemand = append(onDemand, node)
+		}
+	}
+	return onDemand, spot
+}
+
+func (s *labelStrategy) podsOnNode(node *apiv1.Node) ([]*apiv1.Pod, error) {
+	fieldSelector := "spec.nodeName=" + node.Name
+	podList, err := s.client.CoreV1().Pods(apiv1.NamespaceAll).List(metav1.ListOptions{FieldSelector: fieldSelector})
+	if err != nil {
+		return nil, err
+	}
+	pods := make([]*apiv1.Pod, 0, len(podList.Items))
+	for i := range podList.Items {
+		pods = append(pods, &podList.Items[i])
+	}
+	return pods, nil
+}
+
+// findMigrationTarget returns the first spot node that can accommodate all
+// of the given pods, or nil if none can.
+func (s *labelStrategy) findMigrationTarget(pods []*apiv1.Pod, spotNodes []*apiv1.Node) *apiv1.Node {
+	for _, candidate := range spotNodes {
+		fits := true
+		for _, pod := range pods {
+			if err := s.predicateChecker.CheckPredicates(pod, nil, candidate); err != nil {
+				fits = false
+				break
+			}
+		}
+		if fits {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// drainNode cordons the on-demand node and evicts its pods through the eviction subresource,
+// so that PodDisruptionBudgets are honored, giving each MaxGracefulTerminationSec to terminate and
+// retrying each pod's evictions blocked by a budget until MaxPodEvictionTime elapses. The evicted pods are
+// rescheduled onto the target spot node by the normal scheduler.
+func (s *labelStrategy) drainNode(node *apiv1.Node, pods []*apiv1.Pod, target *apiv1.Node) error {
+	node.Spec.Unschedulable = true
+	if _, err := s.client.CoreV1().Nodes().Update(node); err != nil {
+		return err
+	}
+
+	gracePeriod := int64(s.maxGracefulTerminationSec)
+	for _, pod := range pods {
+		deadline := time.Now().Add(s.maxPodEvictionTime)
+		eviction := &policyv1beta1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pod.Name,
+				Namespace: pod.Namespace,
+			},
+			DeleteOptions: &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod},
+		}
+		for {
+			err := s.client.PolicyV1beta1().Evictions(pod.Namespace).Evict(eviction)
+			if err == nil {
+				break
+			}
+			if time.Now().After(deadline) {
+				return err
+			}
+			time.Sleep(time.Second)
+		}
+	}
+	s.recorder.Eventf(node, apiv1.EventTypeNormal, "SpotReschedule",
+		"Evicted pods from on-demand node %s onto spot node %s", node.Name, target.Name)
+	return nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spotrescheduler
+
+import (
+	"testing"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPartitionNodes(t *testing.T) {
+	s := &labelStrategy{spotLabel: "node-role/spot", onDemandLabel: "node-role/on-demand"}
+
+	spotNode := &apiv1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "spot-1", Labels: map[string]string{"node-role/spot": "true"}},
+	}
+	onDemandNode := &apiv1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "on-demand-1", Labels: map[string]string{"node-role/on-demand": "true"}},
+	}
+	unlabeledNode := &apiv1.Node{ObjectMeta: metav1.ObjectMeta{Name: "unlabeled-1"}}
+
+	onDemand, spot := s.partitionNodes([]*apiv1.Node{spotNode, onDemandNode, unlabeledNode})
+
+	if len(spot) != 1 || spot[0].Name != "spot-1" {
+		t.Errorf("expected only spot-1 in spot, got %v", spot)
+	}
+	if len(onDemand) != 1 || onDemand[0].Name != "on-demand-1" {
+		t.Errorf("expected only on-demand-1 in onDemand, got %v", onDemand)
+	}
+}
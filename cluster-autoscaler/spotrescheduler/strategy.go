@@ -0,0 +1,44 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package spotrescheduler implements a strategy that moves pods off
+// on-demand nodes and onto cheaper spot/preemptible node groups whenever
+// they would fit, so that normal scale-down can reclaim the on-demand
+// capacity that's freed up.
+package spotrescheduler
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// Strategy decides which on-demand nodes can be emptied onto spot node
+// groups and drives the migration of their pods.
+type Strategy interface {
+	// Reschedule looks for on-demand nodes whose pods would fit on spot
+	// node groups, cordons them, and evicts their pods so they are
+	// rescheduled onto spot capacity. Nodes it empties are left for the
+	// normal scale-down path to remove.
+	Reschedule(nodes []*apiv1.Node) error
+}
+
+// NoOpStrategy is a Strategy that never reschedules anything. It's used
+// when SpotReschedulerEnabled is false.
+type NoOpStrategy struct{}
+
+// Reschedule is a no-op.
+func (s *NoOpStrategy) Reschedule(nodes []*apiv1.Node) error {
+	return nil
+}
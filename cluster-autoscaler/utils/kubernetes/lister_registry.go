@@ -0,0 +1,144 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kube_client "k8s.io/client-go/kubernetes"
+)
+
+// NodeLister lists all nodes known to the cluster.
+type NodeLister interface {
+	List() ([]*apiv1.Node, error)
+}
+
+// PodLister lists pods, scoped however the lister was configured to scope them.
+type PodLister interface {
+	List() ([]*apiv1.Pod, error)
+}
+
+// ListerRegistry gives CA access to the listers it needs to scan the cluster.
+type ListerRegistry interface {
+	AllNodeLister() NodeLister
+	AllPodLister() PodLister
+}
+
+type listerRegistryImpl struct {
+	nodeLister NodeLister
+	podLister  PodLister
+}
+
+// AllNodeLister returns the lister for all nodes in the cluster.
+func (r *listerRegistryImpl) AllNodeLister() NodeLister {
+	return r.nodeLister
+}
+
+// AllPodLister returns the lister for the pods CA should consider.
+func (r *listerRegistryImpl) AllPodLister() PodLister {
+	return r.podLister
+}
+
+// NewListerRegistryWithSelectors builds a ListerRegistry whose pod lister only returns pods that
+// pass the given namespace and label include/exclude filters: a pod must be in an include
+// namespace (or includeNamespaces is empty, meaning all namespaces) and not in an exclude
+// namespace, and must match includeLabelSelector (nil matches everything) while not matching
+// excludeLabelSelector (nil matches nothing).
+func NewListerRegistryWithSelectors(kubeClient kube_client.Interface,
+	includeNamespaces, excludeNamespaces []string, includeLabelSelector, excludeLabelSelector labels.Selector) ListerRegistry {
+	return &listerRegistryImpl{
+		nodeLister: &nodeLister{kubeClient: kubeClient},
+		podLister: &selectorPodLister{
+			kubeClient:           kubeClient,
+			includeNamespaces:    includeNamespaces,
+			excludeNamespaces:    excludeNamespaces,
+			includeLabelSelector: includeLabelSelector,
+			excludeLabelSelector: excludeLabelSelector,
+		},
+	}
+}
+
+type nodeLister struct {
+	kubeClient kube_client.Interface
+}
+
+func (l *nodeLister) List() ([]*apiv1.Node, error) {
+	nodeList, err := l.kubeClient.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*apiv1.Node, 0, len(nodeList.Items))
+	for i := range nodeList.Items {
+		nodes = append(nodes, &nodeList.Items[i])
+	}
+	return nodes, nil
+}
+
+// selectorPodLister lists pods in includeNamespaces (or every namespace, if empty), applying
+// excludeNamespaces and the label selectors as a post-filter.
+type selectorPodLister struct {
+	kubeClient           kube_client.Interface
+	includeNamespaces    []string
+	excludeNamespaces    []string
+	includeLabelSelector labels.Selector
+	excludeLabelSelector labels.Selector
+}
+
+func (l *selectorPodLister) List() ([]*apiv1.Pod, error) {
+	namespaces := l.includeNamespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{apiv1.NamespaceAll}
+	}
+
+	var pods []*apiv1.Pod
+	for _, namespace := range namespaces {
+		podList, err := l.kubeClient.CoreV1().Pods(namespace).List(metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range podList.Items {
+			pod := &podList.Items[i]
+			if l.matches(pod) {
+				pods = append(pods, pod)
+			}
+		}
+	}
+	return pods, nil
+}
+
+func (l *selectorPodLister) matches(pod *apiv1.Pod) bool {
+	if containsString(l.excludeNamespaces, pod.Namespace) {
+		return false
+	}
+	if l.includeLabelSelector != nil && !l.includeLabelSelector.Matches(labels.Set(pod.Labels)) {
+		return false
+	}
+	if l.excludeLabelSelector != nil && l.excludeLabelSelector.Matches(labels.Set(pod.Labels)) {
+		return false
+	}
+	return true
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
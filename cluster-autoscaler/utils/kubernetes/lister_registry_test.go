@@ -0,0 +1,90 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"sort"
+	"testing"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func pod(namespace, name string, podLabels map[string]string) *apiv1.Pod {
+	return &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: podLabels},
+	}
+}
+
+func podNames(pods []*apiv1.Pod) []string {
+	names := make([]string, 0, len(pods))
+	for _, p := range pods {
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSelectorPodListerNamespaceFilter(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		pod("kube-system", "p1", nil),
+		pod("default", "p2", nil),
+	)
+
+	lister := &selectorPodLister{kubeClient: client, excludeNamespaces: []string{"kube-system"}}
+
+	pods, err := lister.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := podNames(pods); len(got) != 1 || got[0] != "p2" {
+		t.Errorf("expected only p2 to survive the namespace filter, got %v", got)
+	}
+}
+
+func TestSelectorPodListerLabelFilter(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		pod("default", "keep", map[string]string{"tier": "frontend"}),
+		pod("default", "drop-wrong-tier", map[string]string{"tier": "backend"}),
+		pod("default", "drop-excluded", map[string]string{"tier": "frontend", "critical": "true"}),
+	)
+
+	lister := &selectorPodLister{
+		kubeClient:           client,
+		includeLabelSelector: labels.SelectorFromSet(labels.Set{"tier": "frontend"}),
+		excludeLabelSelector: labels.SelectorFromSet(labels.Set{"critical": "true"}),
+	}
+
+	pods, err := lister.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := podNames(pods); len(got) != 1 || got[0] != "keep" {
+		t.Errorf("expected only keep to pass both label selectors, got %v", got)
+	}
+}
+
+func TestContainsString(t *testing.T) {
+	if !containsString([]string{"a", "b"}, "b") {
+		t.Error("expected b to be found")
+	}
+	if containsString([]string{"a", "b"}, "c") {
+		t.Error("did not expect c to be found")
+	}
+}
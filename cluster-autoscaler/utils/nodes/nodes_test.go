@@ -0,0 +1,33 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodes
+
+import "testing"
+
+func TestContainsNode(t *testing.T) {
+	names := []string{"node-1", "node-2"}
+
+	if !ContainsNode(names, "node-1") {
+		t.Error("expected node-1 to be found")
+	}
+	if ContainsNode(names, "node-3") {
+		t.Error("did not expect node-3 to be found")
+	}
+	if ContainsNode(nil, "node-1") {
+		t.Error("did not expect a match against a nil list")
+	}
+}